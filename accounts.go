@@ -3,34 +3,79 @@ package mastodon
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
 // Account hold information for mastodon account.
 type Account struct {
-	ID             int64     `json:"id"`
-	Username       string    `json:"username"`
-	Acct           string    `json:"acct"`
-	DisplayName    string    `json:"display_name"`
-	Locked         bool      `json:"locked"`
-	CreatedAt      time.Time `json:"created_at"`
-	FollowersCount int64     `json:"followers_count"`
-	FollowingCount int64     `json:"following_count"`
-	StatusesCount  int64     `json:"statuses_count"`
-	Note           string    `json:"note"`
-	URL            string    `json:"url"`
-	Avatar         string    `json:"avatar"`
-	AvatarStatic   string    `json:"avatar_static"`
-	Header         string    `json:"header"`
-	HeaderStatic   string    `json:"header_static"`
+	ID             string          `json:"id"`
+	Username       string          `json:"username"`
+	Acct           string          `json:"acct"`
+	DisplayName    string          `json:"display_name"`
+	Locked         bool            `json:"locked"`
+	CreatedAt      time.Time       `json:"created_at"`
+	FollowersCount int64           `json:"followers_count"`
+	FollowingCount int64           `json:"following_count"`
+	StatusesCount  int64           `json:"statuses_count"`
+	Note           string          `json:"note"`
+	URL            string          `json:"url"`
+	Avatar         string          `json:"avatar"`
+	AvatarStatic   string          `json:"avatar_static"`
+	Header         string          `json:"header"`
+	HeaderStatic   string          `json:"header_static"`
+	Emojis         []Emoji         `json:"emojis"`
+	Moved          *Account        `json:"moved"`
+	Fields         []Field         `json:"fields"`
+	Bot            bool            `json:"bot"`
+	Source         *AccountSource  `json:"source"`
+	Pleroma        *AccountPleroma `json:"pleroma"`
+}
+
+// Emoji holds information for a custom emoji, usable in display names, bios
+// and profile fields.
+type Emoji struct {
+	ShortCode       string `json:"shortcode"`
+	StaticURL       string `json:"static_url"`
+	URL             string `json:"url"`
+	VisibleInPicker bool   `json:"visible_in_picker"`
+}
+
+// Field is a name/value pair that shows up as a profile metadata row. A
+// non-nil VerifiedAt means Mastodon found a rel="me" link back to the
+// account's profile URL at Value.
+type Field struct {
+	Name       string     `json:"name"`
+	Value      string     `json:"value"`
+	VerifiedAt *time.Time `json:"verified_at"`
+}
+
+// AccountSource holds the account owner's private settings, only present
+// when fetching or updating the current user's own account.
+type AccountSource struct {
+	Privacy   string   `json:"privacy"`
+	Language  string   `json:"language"`
+	Note      *string  `json:"note"`
+	Sensitive *bool    `json:"sensitive"`
+	Fields    *[]Field `json:"fields"`
+}
+
+// ParseID converts an account/relationship ID into its numeric form, for
+// callers that still need an int64 representation. IDs are opaque strings
+// on the wire: modern Mastodon snowflake IDs can overflow a JSON int64 in
+// some clients, and Pleroma/Akkoma/GoToSocial hand out non-numeric IDs
+// (UUID/FlakeId), so this will fail on those servers.
+func ParseID(id string) (int64, error) {
+	return strconv.ParseInt(id, 10, 64)
 }
 
 // GetAccount return Account.
-func (c *Client) GetAccount(ctx context.Context, id int) (*Account, error) {
+func (c *Client) GetAccount(ctx context.Context, id string) (*Account, error) {
 	var account Account
-	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%d", id), nil, &account)
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%s", url.PathEscape(id)), nil, &account)
 	if err != nil {
 		return nil, err
 	}
@@ -53,10 +98,31 @@ type Profile struct {
 	// If it is empty, update it with empty.
 	DisplayName *string
 	Note        *string
+	Locked      *bool
+	Bot         *bool
 
-	// Set the base64 encoded character string of the image.
-	Avatar string
-	Header string
+	// Avatar/Header, when non-nil, are uploaded as multipart file parts
+	// using AvatarName/HeaderName as the filename Mastodon records.
+	Avatar     io.Reader
+	AvatarName string
+	Header     io.Reader
+	HeaderName string
+
+	// Fields is rendered as the profile metadata rows shown on the
+	// account page; each entry overwrites the field at that index,
+	// so the full desired set must be sent on every update.
+	Fields []Field
+
+	// Source configures the account owner's private posting defaults.
+	Source *ProfileSource
+}
+
+// ProfileSource updates the subset of AccountSource that Mastodon accepts
+// on the update_credentials endpoint.
+type ProfileSource struct {
+	Privacy   *string
+	Sensitive *bool
+	Language  *string
 }
 
 // AccountUpdate updates the information of the current user.
@@ -68,14 +134,48 @@ func (c *Client) AccountUpdate(ctx context.Context, profile *Profile) (*Account,
 	if profile.Note != nil {
 		params.Set("note", *profile.Note)
 	}
-	if profile.Avatar != "" {
-		params.Set("avatar", profile.Avatar)
+	if profile.Locked != nil {
+		params.Set("locked", strconv.FormatBool(*profile.Locked))
 	}
-	if profile.Header != "" {
-		params.Set("header", profile.Header)
+	if profile.Bot != nil {
+		params.Set("bot", strconv.FormatBool(*profile.Bot))
+	}
+	for i, field := range profile.Fields {
+		params.Set(fmt.Sprintf("fields_attributes[%d][name]", i), field.Name)
+		params.Set(fmt.Sprintf("fields_attributes[%d][value]", i), field.Value)
+	}
+	if profile.Source != nil {
+		if profile.Source.Privacy != nil {
+			params.Set("source[privacy]", *profile.Source.Privacy)
+		}
+		if profile.Source.Sensitive != nil {
+			params.Set("source[sensitive]", strconv.FormatBool(*profile.Source.Sensitive))
+		}
+		if profile.Source.Language != nil {
+			params.Set("source[language]", *profile.Source.Language)
+		}
 	}
 
 	var account Account
+	if profile.Avatar != nil || profile.Header != nil {
+		files := map[string]namedReader{}
+		if profile.Avatar != nil {
+			files["avatar"] = namedReader{Name: profile.AvatarName, Reader: profile.Avatar}
+		}
+		if profile.Header != nil {
+			files["header"] = namedReader{Name: profile.HeaderName, Reader: profile.Header}
+		}
+		body, contentType, err := multipartBody(params, files)
+		if err != nil {
+			return nil, err
+		}
+		err = c.doAPIMultipart(ctx, http.MethodPatch, "/api/v1/accounts/update_credentials", body, contentType, &account)
+		if err != nil {
+			return nil, err
+		}
+		return &account, nil
+	}
+
 	err := c.doAPI(ctx, http.MethodPatch, "/api/v1/accounts/update_credentials", params, &account)
 	if err != nil {
 		return nil, err
@@ -84,9 +184,12 @@ func (c *Client) AccountUpdate(ctx context.Context, profile *Profile) (*Account,
 }
 
 // GetAccountStatuses return statuses by specified accuont.
-func (c *Client) GetAccountStatuses(ctx context.Context, id int64) ([]*Status, error) {
+func (c *Client) GetAccountStatuses(ctx context.Context, id string, pg *Pagination) ([]*Status, error) {
+	params := url.Values{}
+	pg.setValues(params)
+
 	var statuses []*Status
-	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%d/statuses", id), nil, &statuses)
+	err := c.doAPIPaged(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%s/statuses", url.PathEscape(id)), params, &statuses, pg)
 	if err != nil {
 		return nil, err
 	}
@@ -94,9 +197,12 @@ func (c *Client) GetAccountStatuses(ctx context.Context, id int64) ([]*Status, e
 }
 
 // GetAccountFollowers return followers list.
-func (c *Client) GetAccountFollowers(ctx context.Context, id int64) ([]*Account, error) {
+func (c *Client) GetAccountFollowers(ctx context.Context, id string, pg *Pagination) ([]*Account, error) {
+	params := url.Values{}
+	pg.setValues(params)
+
 	var accounts []*Account
-	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%d/followers", id), nil, &accounts)
+	err := c.doAPIPaged(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%s/followers", url.PathEscape(id)), params, &accounts, pg)
 	if err != nil {
 		return nil, err
 	}
@@ -104,9 +210,12 @@ func (c *Client) GetAccountFollowers(ctx context.Context, id int64) ([]*Account,
 }
 
 // GetAccountFollowing return following list.
-func (c *Client) GetAccountFollowing(ctx context.Context, id int64) ([]*Account, error) {
+func (c *Client) GetAccountFollowing(ctx context.Context, id string, pg *Pagination) ([]*Account, error) {
+	params := url.Values{}
+	pg.setValues(params)
+
 	var accounts []*Account
-	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%d/following", id), nil, &accounts)
+	err := c.doAPIPaged(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%s/following", url.PathEscape(id)), params, &accounts, pg)
 	if err != nil {
 		return nil, err
 	}
@@ -114,9 +223,12 @@ func (c *Client) GetAccountFollowing(ctx context.Context, id int64) ([]*Account,
 }
 
 // GetBlocks return block list.
-func (c *Client) GetBlocks(ctx context.Context) ([]*Account, error) {
+func (c *Client) GetBlocks(ctx context.Context, pg *Pagination) ([]*Account, error) {
+	params := url.Values{}
+	pg.setValues(params)
+
 	var accounts []*Account
-	err := c.doAPI(ctx, http.MethodGet, "/api/v1/blocks", nil, &accounts)
+	err := c.doAPIPaged(ctx, http.MethodGet, "/api/v1/blocks", params, &accounts, pg)
 	if err != nil {
 		return nil, err
 	}
@@ -125,18 +237,18 @@ func (c *Client) GetBlocks(ctx context.Context) ([]*Account, error) {
 
 // Relationship hold information for relation-ship to the account.
 type Relationship struct {
-	ID         int64 `json:"id"`
-	Following  bool  `json:"following"`
-	FollowedBy bool  `json:"followed_by"`
-	Blocking   bool  `json:"blocking"`
-	Muting     bool  `json:"muting"`
-	Requested  bool  `json:"requested"`
+	ID         string `json:"id"`
+	Following  bool   `json:"following"`
+	FollowedBy bool   `json:"followed_by"`
+	Blocking   bool   `json:"blocking"`
+	Muting     bool   `json:"muting"`
+	Requested  bool   `json:"requested"`
 }
 
 // AccountFollow follow the account.
-func (c *Client) AccountFollow(ctx context.Context, id int64) (*Relationship, error) {
+func (c *Client) AccountFollow(ctx context.Context, id string) (*Relationship, error) {
 	var relationship Relationship
-	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%d/follow", id), nil, &relationship)
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%s/follow", url.PathEscape(id)), nil, &relationship)
 	if err != nil {
 		return nil, err
 	}
@@ -144,9 +256,9 @@ func (c *Client) AccountFollow(ctx context.Context, id int64) (*Relationship, er
 }
 
 // AccountUnfollow unfollow the account.
-func (c *Client) AccountUnfollow(ctx context.Context, id int64) (*Relationship, error) {
+func (c *Client) AccountUnfollow(ctx context.Context, id string) (*Relationship, error) {
 	var relationship Relationship
-	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%d/unfollow", id), nil, &relationship)
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%s/unfollow", url.PathEscape(id)), nil, &relationship)
 	if err != nil {
 		return nil, err
 	}
@@ -154,9 +266,9 @@ func (c *Client) AccountUnfollow(ctx context.Context, id int64) (*Relationship,
 }
 
 // AccountBlock block the account.
-func (c *Client) AccountBlock(ctx context.Context, id int64) (*Relationship, error) {
+func (c *Client) AccountBlock(ctx context.Context, id string) (*Relationship, error) {
 	var relationship Relationship
-	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%d/block", id), nil, &relationship)
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%s/block", url.PathEscape(id)), nil, &relationship)
 	if err != nil {
 		return nil, err
 	}
@@ -164,9 +276,9 @@ func (c *Client) AccountBlock(ctx context.Context, id int64) (*Relationship, err
 }
 
 // AccountUnblock unblock the account.
-func (c *Client) AccountUnblock(ctx context.Context, id int64) (*Relationship, error) {
+func (c *Client) AccountUnblock(ctx context.Context, id string) (*Relationship, error) {
 	var relationship Relationship
-	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%d/unblock", id), nil, &relationship)
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%s/unblock", url.PathEscape(id)), nil, &relationship)
 	if err != nil {
 		return nil, err
 	}
@@ -174,9 +286,9 @@ func (c *Client) AccountUnblock(ctx context.Context, id int64) (*Relationship, e
 }
 
 // AccountMute mute the account.
-func (c *Client) AccountMute(ctx context.Context, id int64) (*Relationship, error) {
+func (c *Client) AccountMute(ctx context.Context, id string) (*Relationship, error) {
 	var relationship Relationship
-	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%d/mute", id), nil, &relationship)
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%s/mute", url.PathEscape(id)), nil, &relationship)
 	if err != nil {
 		return nil, err
 	}
@@ -184,9 +296,9 @@ func (c *Client) AccountMute(ctx context.Context, id int64) (*Relationship, erro
 }
 
 // AccountUnmute unmute the account.
-func (c *Client) AccountUnmute(ctx context.Context, id int64) (*Relationship, error) {
+func (c *Client) AccountUnmute(ctx context.Context, id string) (*Relationship, error) {
 	var relationship Relationship
-	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%d/unmute", id), nil, &relationship)
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/accounts/%s/unmute", url.PathEscape(id)), nil, &relationship)
 	if err != nil {
 		return nil, err
 	}
@@ -194,10 +306,10 @@ func (c *Client) AccountUnmute(ctx context.Context, id int64) (*Relationship, er
 }
 
 // GetAccountRelationship return relationship for the account.
-func (c *Client) GetAccountRelationships(ctx context.Context, ids []int64) ([]*Relationship, error) {
+func (c *Client) GetAccountRelationships(ctx context.Context, ids []string) ([]*Relationship, error) {
 	params := url.Values{}
 	for _, id := range ids {
-		params.Add("id[]", fmt.Sprint(id))
+		params.Add("id[]", id)
 	}
 
 	var relationships []*Relationship
@@ -205,17 +317,32 @@ func (c *Client) GetAccountRelationships(ctx context.Context, ids []int64) ([]*R
 	if err != nil {
 		return nil, err
 	}
+	if len(relationships) > 0 {
+		return relationships, nil
+	}
+
+	// Some Pleroma/Akkoma servers return an empty array here and only
+	// surface the relationship via each Account's embedded pleroma object.
+	for _, id := range ids {
+		account, err := c.GetAccount(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if rel := pleromaRelationship(account); rel != nil {
+			relationships = append(relationships, rel)
+		}
+	}
 	return relationships, nil
 }
 
 // AccountsSearch search accounts by query.
-func (c *Client) AccountsSearch(ctx context.Context, q string, limit int64) ([]*Account, error) {
+func (c *Client) AccountsSearch(ctx context.Context, q string, pg *Pagination) ([]*Account, error) {
 	params := url.Values{}
 	params.Set("q", q)
-	params.Set("limit", fmt.Sprint(limit))
+	pg.setValues(params)
 
 	var accounts []*Account
-	err := c.doAPI(ctx, http.MethodGet, "/api/v1/accounts/search", params, &accounts)
+	err := c.doAPIPaged(ctx, http.MethodGet, "/api/v1/accounts/search", params, &accounts, pg)
 	if err != nil {
 		return nil, err
 	}
@@ -236,9 +363,12 @@ func (c *Client) FollowRemoteUser(ctx context.Context, uri string) (*Account, er
 }
 
 // GetFollowRequests return follow-requests.
-func (c *Client) GetFollowRequests(ctx context.Context) ([]*Account, error) {
+func (c *Client) GetFollowRequests(ctx context.Context, pg *Pagination) ([]*Account, error) {
+	params := url.Values{}
+	pg.setValues(params)
+
 	var accounts []*Account
-	err := c.doAPI(ctx, http.MethodGet, "/api/v1/follow_requests", nil, &accounts)
+	err := c.doAPIPaged(ctx, http.MethodGet, "/api/v1/follow_requests", params, &accounts, pg)
 	if err != nil {
 		return nil, err
 	}
@@ -246,19 +376,22 @@ func (c *Client) GetFollowRequests(ctx context.Context) ([]*Account, error) {
 }
 
 // FollowRequestAuthorize is authorize the follow request of user with id.
-func (c *Client) FollowRequestAuthorize(ctx context.Context, id int64) error {
-	return c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/follow_requests/%d/authorize", id), nil, nil)
+func (c *Client) FollowRequestAuthorize(ctx context.Context, id string) error {
+	return c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/follow_requests/%s/authorize", url.PathEscape(id)), nil, nil)
 }
 
 // FollowRequestReject is rejects the follow request of user with id.
-func (c *Client) FollowRequestReject(ctx context.Context, id int64) error {
-	return c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/follow_requests/%d/reject", id), nil, nil)
+func (c *Client) FollowRequestReject(ctx context.Context, id string) error {
+	return c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/follow_requests/%s/reject", url.PathEscape(id)), nil, nil)
 }
 
 // GetMutes returns the list of users muted by the current user.
-func (c *Client) GetMutes(ctx context.Context) ([]*Account, error) {
+func (c *Client) GetMutes(ctx context.Context, pg *Pagination) ([]*Account, error) {
+	params := url.Values{}
+	pg.setValues(params)
+
 	var accounts []*Account
-	err := c.doAPI(ctx, http.MethodGet, "/api/v1/mutes", nil, &accounts)
+	err := c.doAPIPaged(ctx, http.MethodGet, "/api/v1/mutes", params, &accounts, pg)
 	if err != nil {
 		return nil, err
 	}
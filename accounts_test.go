@@ -0,0 +1,79 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestClient(server string) *Client {
+	return &Client{config: &Config{Server: server, AccessToken: "test-token"}}
+}
+
+// TestGetAccountRelationshipsPleromaFallback covers the Pleroma/Akkoma case
+// where /api/v1/accounts/relationships returns an empty array and the
+// relationship has to be read back out of each account's embedded pleroma
+// object instead.
+func TestGetAccountRelationshipsPleromaFallback(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/accounts/relationships":
+			w.Write([]byte(`[]`))
+		case r.URL.Path == "/api/v1/accounts/1" || r.URL.Path == "/api/v1/accounts/2":
+			id := r.URL.Path[len("/api/v1/accounts/"):]
+			json.NewEncoder(w).Encode(&Account{
+				ID: id,
+				Pleroma: &AccountPleroma{
+					Relationship: &Relationship{ID: id, Following: true},
+				},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := newTestClient(ts.URL)
+	relationships, err := client.GetAccountRelationships(context.Background(), []string{"1", "2"})
+	if err != nil {
+		t.Fatalf("GetAccountRelationships: %v", err)
+	}
+	if len(relationships) != 2 {
+		t.Fatalf("got %d relationships, want 2", len(relationships))
+	}
+	for i, id := range []string{"1", "2"} {
+		if relationships[i].ID != id {
+			t.Errorf("relationships[%d].ID = %q, want %q", i, relationships[i].ID, id)
+		}
+		if !relationships[i].Following {
+			t.Errorf("relationships[%d].Following = false, want true", i)
+		}
+	}
+}
+
+// TestGetAccountRelationshipsNoFallback covers the normal Mastodon case
+// where the relationships array is populated directly and no per-id
+// pleroma lookup happens.
+func TestGetAccountRelationshipsNoFallback(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/relationships" {
+			t.Errorf("unexpected fallback request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode([]*Relationship{{ID: "1", Following: true}})
+	}))
+	defer ts.Close()
+
+	client := newTestClient(ts.URL)
+	relationships, err := client.GetAccountRelationships(context.Background(), []string{"1"})
+	if err != nil {
+		t.Fatalf("GetAccountRelationships: %v", err)
+	}
+	if len(relationships) != 1 || relationships[0].ID != "1" {
+		t.Fatalf("got %+v, want a single relationship with ID 1", relationships)
+	}
+}
@@ -0,0 +1,224 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/mattn/go-mastodon"
+)
+
+// Bot dispatches incoming notifications through a configured list of
+// Rules, acting on the user's behalf over the streaming API.
+type Bot struct {
+	client *mastodon.Client
+	config *Config
+	state  *state
+	limit  *limiter
+
+	// followersMu guards followers, which is replaced wholesale by the
+	// periodic background refresh while match() reads it from the
+	// stream-processing goroutine.
+	followersMu sync.RWMutex
+	followers   map[string]bool
+}
+
+// New builds a Bot that uses client to act and cfg to decide what to do
+// with each notification.
+func New(client *mastodon.Client, cfg *Config) (*Bot, error) {
+	st, err := loadState(cfg.StateFile)
+	if err != nil {
+		return nil, fmt.Errorf("bot: load state: %w", err)
+	}
+	return &Bot{
+		client:    client,
+		config:    cfg,
+		state:     st,
+		limit:     newLimiter(cfg.RateLimit, cfg.Window),
+		followers: map[string]bool{},
+	}, nil
+}
+
+// Run opens the user stream and dispatches notifications against the
+// configured rules until ctx is cancelled or the stream errors out.
+func (b *Bot) Run(ctx context.Context) error {
+	if err := b.refreshFollowers(ctx); err != nil {
+		return fmt.Errorf("bot: initial follower fetch: %w", err)
+	}
+	go b.refreshFollowersPeriodically(ctx, 10*time.Minute)
+
+	events, err := b.client.StreamingUser(ctx)
+	if err != nil {
+		return fmt.Errorf("bot: open user stream: %w", err)
+	}
+
+	for ev := range events {
+		n, ok := ev.(*mastodon.NotificationEvent)
+		if !ok {
+			continue
+		}
+		if err := b.handle(ctx, n.Notification); err != nil {
+			log.Printf("bot: handle notification %s: %v", n.Notification.ID, err)
+		}
+	}
+	return nil
+}
+
+func (b *Bot) refreshFollowersPeriodically(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := b.refreshFollowers(ctx); err != nil {
+				log.Printf("bot: refresh followers: %v", err)
+			}
+		}
+	}
+}
+
+func (b *Bot) refreshFollowers(ctx context.Context) error {
+	me, err := b.client.GetAccountCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	followers := map[string]bool{}
+	pg := &mastodon.Pagination{Limit: 200}
+	for {
+		page, err := b.client.GetAccountFollowers(ctx, me.ID, pg)
+		if err != nil {
+			return err
+		}
+		for _, a := range page {
+			followers[a.Acct] = true
+		}
+		if pg.MaxID == "" {
+			break
+		}
+	}
+
+	b.followersMu.Lock()
+	b.followers = followers
+	b.followersMu.Unlock()
+	return nil
+}
+
+// isFollower reports whether acct is in the most recently fetched
+// followers snapshot.
+func (b *Bot) isFollower(acct string) bool {
+	b.followersMu.RLock()
+	defer b.followersMu.RUnlock()
+	return b.followers[acct]
+}
+
+// handle runs the first rule matching n, skipping notifications already
+// recorded in the bot's persisted state.
+func (b *Bot) handle(ctx context.Context, n *mastodon.Notification) error {
+	seen, err := b.state.seen(n.ID)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return nil
+	}
+
+	rule := b.match(n)
+	if rule == nil {
+		return nil
+	}
+	return b.act(ctx, rule, n)
+}
+
+func (b *Bot) match(n *mastodon.Notification) *Rule {
+	for i := range b.config.Rules {
+		r := &b.config.Rules[i]
+		if r.On != n.Type {
+			continue
+		}
+		if r.From == "followers" && !b.isFollower(n.Account.Acct) {
+			continue
+		}
+		if r.IfVisibility != "" && (n.Status == nil || n.Status.Visibility != r.IfVisibility) {
+			continue
+		}
+		return r
+	}
+	return nil
+}
+
+func (b *Bot) act(ctx context.Context, rule *Rule, n *mastodon.Notification) error {
+	switch rule.Action {
+	case "", "ignore":
+		return nil
+	case "reply":
+		return b.reply(ctx, rule, n)
+	case "reblog":
+		if n.Status == nil {
+			return nil
+		}
+		return b.withRateLimit(n.Account.Acct, func() error {
+			_, err := b.client.Reblog(ctx, n.Status.ID)
+			return err
+		})
+	case "favourite":
+		if n.Status == nil {
+			return nil
+		}
+		return b.withRateLimit(n.Account.Acct, func() error {
+			_, err := b.client.Favourite(ctx, n.Status.ID)
+			return err
+		})
+	case "follow_back":
+		return b.withRateLimit(n.Account.Acct, func() error {
+			_, err := b.client.AccountFollow(ctx, n.Account.ID)
+			return err
+		})
+	default:
+		return fmt.Errorf("bot: unknown action %q", rule.Action)
+	}
+}
+
+func (b *Bot) reply(ctx context.Context, rule *Rule, n *mastodon.Notification) error {
+	body, err := render(rule.Template, n)
+	if err != nil {
+		return err
+	}
+	var inReplyTo string
+	if n.Status != nil {
+		inReplyTo = n.Status.ID
+	}
+	return b.withRateLimit(n.Account.Acct, func() error {
+		_, err := b.client.PostStatus(ctx, &mastodon.Toot{
+			Status:      "@" + n.Account.Acct + " " + body,
+			InReplyToID: inReplyTo,
+			Visibility:  rule.Visibility,
+		})
+		return err
+	})
+}
+
+func (b *Bot) withRateLimit(acct string, fn func() error) error {
+	if !b.limit.allow(acct, time.Now()) {
+		return nil
+	}
+	return fn()
+}
+
+func render(tmpl string, n *mastodon.Notification) (string, error) {
+	t, err := template.New("rule").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, n); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/mattn/go-mastodon"
+)
+
+func TestMatch(t *testing.T) {
+	b := &Bot{
+		config: &Config{
+			Rules: []Rule{
+				{On: "follow", From: "followers", Action: "ignore"},
+				{On: "follow", Action: "follow_back"},
+				{On: "mention", IfVisibility: "public", Action: "reblog"},
+			},
+		},
+		followers: map[string]bool{"known@example.com": true},
+	}
+
+	tests := []struct {
+		name       string
+		n          *mastodon.Notification
+		wantAction string
+		wantNil    bool
+	}{
+		{
+			name:       "follow from a stranger falls through to the catch-all",
+			n:          &mastodon.Notification{Type: "follow", Account: mastodon.Account{Acct: "stranger@example.com"}},
+			wantAction: "follow_back",
+		},
+		{
+			name:       "follow from a known follower",
+			n:          &mastodon.Notification{Type: "follow", Account: mastodon.Account{Acct: "known@example.com"}},
+			wantAction: "ignore",
+		},
+		{
+			name:       "public mention matches",
+			n:          &mastodon.Notification{Type: "mention", Status: &mastodon.Status{Visibility: "public"}},
+			wantAction: "reblog",
+		},
+		{
+			name:    "private mention does not match the public-only rule",
+			n:       &mastodon.Notification{Type: "mention", Status: &mastodon.Status{Visibility: "private"}},
+			wantNil: true,
+		},
+		{
+			name:    "unconfigured notification type",
+			n:       &mastodon.Notification{Type: "favourite"},
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := b.match(tt.n)
+			if tt.wantNil {
+				if rule != nil {
+					t.Fatalf("match() = %+v, want nil", rule)
+				}
+				return
+			}
+			if rule == nil || rule.Action != tt.wantAction {
+				t.Fatalf("match() action = %+v, want %q", rule, tt.wantAction)
+			}
+		})
+	}
+}
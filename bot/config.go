@@ -0,0 +1,64 @@
+// Package bot implements a notification-driven bot on top of the
+// mastodon package's streaming and account APIs: it watches the user
+// stream and runs a configurable set of rules against each incoming
+// notification.
+package bot
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the bot's rule set and runtime tuning, loaded from a YAML
+// file. Server credentials are not part of this file; the bot reuses
+// whatever *mastodon.Client the caller already authenticated.
+type Config struct {
+	Rules []Rule `yaml:"rules"`
+
+	// StateFile persists the set of notification IDs already handled, so
+	// a restart does not re-run actions (e.g. re-welcoming everyone) for
+	// notifications seen before the restart.
+	StateFile string `yaml:"state_file"`
+
+	// RateLimit caps outbound posts per account within Window, so a rule
+	// cannot be driven into a reply loop with another bot.
+	RateLimit int           `yaml:"rate_limit"`
+	Window    time.Duration `yaml:"window"`
+}
+
+// Rule matches a notification and describes what to do with it. Rules
+// are evaluated in order and the first match wins.
+type Rule struct {
+	On           string `yaml:"on"`            // notification type: "follow", "mention", "favourite", "reblog"
+	From         string `yaml:"from"`          // "" (anyone) or "followers"
+	IfVisibility string `yaml:"if_visibility"` // only for "mention": require this status visibility
+	Action       string `yaml:"action"`        // "reply", "reblog", "favourite", "follow_back", "ignore"
+	Template     string `yaml:"template"`      // text/template body, rendered with the *mastodon.Notification
+	Visibility   string `yaml:"visibility"`    // visibility to post "reply" actions with
+}
+
+// Load reads and parses a YAML bot config from path, filling in defaults
+// for anything left unset.
+func Load(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("bot: read config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("bot: parse config %s: %w", path, err)
+	}
+	if cfg.RateLimit == 0 {
+		cfg.RateLimit = 1
+	}
+	if cfg.Window == 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.StateFile == "" {
+		cfg.StateFile = "mstdn-bot-state.json"
+	}
+	return &cfg, nil
+}
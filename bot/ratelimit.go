@@ -0,0 +1,42 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+// limiter caps how many outbound posts the bot will send for a given
+// account within a rolling window, so a rule cannot be driven into a
+// reply loop with another bot.
+type limiter struct {
+	max    int
+	window time.Duration
+
+	mu   sync.Mutex
+	hits map[string][]time.Time
+}
+
+func newLimiter(max int, window time.Duration) *limiter {
+	return &limiter{max: max, window: window, hits: map[string][]time.Time{}}
+}
+
+// allow reports whether another post for acct is permitted right now,
+// recording it if so.
+func (l *limiter) allow(acct string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := now.Add(-l.window)
+	kept := l.hits[acct][:0]
+	for _, t := range l.hits[acct] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) >= l.max {
+		l.hits[acct] = kept
+		return false
+	}
+	l.hits[acct] = append(kept, now)
+	return true
+}
@@ -0,0 +1,28 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	l := newLimiter(2, time.Minute)
+	now := time.Now()
+
+	if !l.allow("a@example.com", now) {
+		t.Fatal("first post should be allowed")
+	}
+	if !l.allow("a@example.com", now) {
+		t.Fatal("second post within the limit should be allowed")
+	}
+	if l.allow("a@example.com", now) {
+		t.Fatal("third post should be rate-limited")
+	}
+	if !l.allow("b@example.com", now) {
+		t.Fatal("a different account should have its own budget")
+	}
+
+	if !l.allow("a@example.com", now.Add(2*time.Minute)) {
+		t.Fatal("post should be allowed again once the window has passed")
+	}
+}
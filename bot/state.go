@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// maxHandled bounds how many notification IDs are remembered on disk.
+// Notification IDs are monotonically increasing, so once more than
+// maxHandled have been seen the oldest can never recur and are dropped.
+const maxHandled = 10000
+
+// state is a disk-backed set of notification IDs the bot has already
+// acted on, so a restart does not repeat actions for notifications seen
+// before the restart. order records handled in the order they were
+// seen so the oldest can be evicted once the set grows past maxHandled;
+// it is what gets persisted, with handled rebuilt from it on load.
+type state struct {
+	path string
+
+	mu      sync.Mutex
+	handled map[string]bool
+	order   []string
+}
+
+func loadState(path string) (*state, error) {
+	s := &state{path: path, handled: map[string]bool{}}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.order); err != nil {
+		return nil, err
+	}
+	for _, id := range s.order {
+		s.handled[id] = true
+	}
+	return s, nil
+}
+
+// seen reports whether id has already been handled. If it has not, it is
+// marked handled and the set is persisted before returning.
+func (s *state) seen(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handled[id] {
+		return true, nil
+	}
+	s.handled[id] = true
+	s.order = append(s.order, id)
+	if over := len(s.order) - maxHandled; over > 0 {
+		for _, evicted := range s.order[:over] {
+			delete(s.handled, evicted)
+		}
+		s.order = s.order[over:]
+	}
+
+	b, err := json.Marshal(s.order)
+	if err != nil {
+		return false, err
+	}
+	return false, os.WriteFile(s.path, b, 0600)
+}
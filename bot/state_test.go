@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestStateSeen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := loadState(path)
+	if err != nil {
+		t.Fatalf("loadState: %v", err)
+	}
+
+	seen, err := s.seen("1")
+	if err != nil {
+		t.Fatalf("seen: %v", err)
+	}
+	if seen {
+		t.Fatal("notification 1 should not be seen yet")
+	}
+
+	seen, err = s.seen("1")
+	if err != nil {
+		t.Fatalf("seen: %v", err)
+	}
+	if !seen {
+		t.Fatal("notification 1 should now be marked seen")
+	}
+
+	// A fresh state loaded from disk must remember what was handled
+	// before a restart.
+	reloaded, err := loadState(path)
+	if err != nil {
+		t.Fatalf("loadState (reload): %v", err)
+	}
+	seen, err = reloaded.seen("1")
+	if err != nil {
+		t.Fatalf("seen (reload): %v", err)
+	}
+	if !seen {
+		t.Fatal("notification 1 should still be seen after a restart")
+	}
+}
+
+func TestStateSeenEvictsOldest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := loadState(path)
+	if err != nil {
+		t.Fatalf("loadState: %v", err)
+	}
+
+	for i := 0; i < maxHandled+10; i++ {
+		if _, err := s.seen(fmt.Sprintf("%d", i)); err != nil {
+			t.Fatalf("seen: %v", err)
+		}
+	}
+	if len(s.handled) != maxHandled {
+		t.Fatalf("handled set should be capped at %d, got %d", maxHandled, len(s.handled))
+	}
+
+	if seen, _ := s.seen("0"); seen {
+		t.Fatal("evicted notification 0 should no longer be marked seen")
+	}
+	if seen, _ := s.seen(fmt.Sprintf("%d", maxHandled+9)); !seen {
+		t.Fatal("most recently seen notification should still be marked seen")
+	}
+}
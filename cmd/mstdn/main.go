@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/mattn/go-mastodon"
+	"github.com/mattn/go-mastodon/bot"
 	"github.com/mattn/go-tty"
 	"github.com/urfave/cli"
 	"golang.org/x/net/html"
@@ -143,6 +144,15 @@ func authenticate(client *mastodon.Client, config *mastodon.Config, file string)
 	return nil
 }
 
+// paginationFromFlags builds a *mastodon.Pagination out of the --limit and
+// --max-id flags shared by commands that list paginated results.
+func paginationFromFlags(c *cli.Context) *mastodon.Pagination {
+	return &mastodon.Pagination{
+		Limit: c.Int64("limit"),
+		MaxID: c.String("max-id"),
+	}
+}
+
 func argstr(c *cli.Context) string {
 	a := []string{}
 	for i := 0; i < c.NArg(); i++ {
@@ -193,8 +203,18 @@ func makeApp() *cli.App {
 			Action: cmdStream,
 		},
 		{
-			Name:   "timeline",
-			Usage:  "show timeline",
+			Name:  "timeline",
+			Usage: "show timeline",
+			Flags: []cli.Flag{
+				cli.Int64Flag{
+					Name:  "limit",
+					Usage: "max number of statuses to fetch",
+				},
+				cli.StringFlag{
+					Name:  "max-id",
+					Usage: "fetch statuses older than this ID",
+				},
+			},
 			Action: cmdTimeline,
 		},
 		{
@@ -218,8 +238,18 @@ func makeApp() *cli.App {
 			Action: cmdSearch,
 		},
 		{
-			Name:   "followers",
-			Usage:  "show followers",
+			Name:  "followers",
+			Usage: "show followers",
+			Flags: []cli.Flag{
+				cli.Int64Flag{
+					Name:  "limit",
+					Usage: "max number of followers to fetch",
+				},
+				cli.StringFlag{
+					Name:  "max-id",
+					Usage: "fetch followers older than this ID",
+				},
+			},
 			Action: cmdFollowers,
 		},
 		{
@@ -227,10 +257,106 @@ func makeApp() *cli.App {
 			Usage:  "upload file",
 			Action: cmdUpload,
 		},
+		{
+			Name:      "avatar",
+			Usage:     "set account avatar image",
+			ArgsUsage: "FILE",
+			Action:    cmdAvatar,
+		},
+		{
+			Name:      "header",
+			Usage:     "set account header image",
+			ArgsUsage: "FILE",
+			Action:    cmdHeader,
+		},
+		{
+			Name:      "bot",
+			Usage:     "run the notification-driven bot",
+			ArgsUsage: "CONFIG",
+			Action:    cmdBot,
+		},
 	}
 	return app
 }
 
+func cmdTimeline(c *cli.Context) error {
+	client := c.App.Metadata["client"].(*mastodon.Client)
+	me, err := client.GetAccountCurrentUser(context.Background())
+	if err != nil {
+		return err
+	}
+	statuses, err := client.GetAccountStatuses(context.Background(), me.ID, paginationFromFlags(c))
+	if err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		fmt.Printf("%s: %s\n", s.Account.Acct, textContent(s.Content))
+	}
+	return nil
+}
+
+func cmdFollowers(c *cli.Context) error {
+	client := c.App.Metadata["client"].(*mastodon.Client)
+	me, err := client.GetAccountCurrentUser(context.Background())
+	if err != nil {
+		return err
+	}
+	followers, err := client.GetAccountFollowers(context.Background(), me.ID, paginationFromFlags(c))
+	if err != nil {
+		return err
+	}
+	for _, f := range followers {
+		fmt.Println(f.Acct)
+	}
+	return nil
+}
+
+func cmdBot(c *cli.Context) error {
+	client := c.App.Metadata["client"].(*mastodon.Client)
+
+	cfg, err := bot.Load(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	b, err := bot.New(client, cfg)
+	if err != nil {
+		return err
+	}
+	return b.Run(context.Background())
+}
+
+func cmdAvatar(c *cli.Context) error {
+	client := c.App.Metadata["client"].(*mastodon.Client)
+	filename := c.Args().Get(0)
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = client.AccountUpdate(context.Background(), &mastodon.Profile{
+		Avatar:     f,
+		AvatarName: filepath.Base(filename),
+	})
+	return err
+}
+
+func cmdHeader(c *cli.Context) error {
+	client := c.App.Metadata["client"].(*mastodon.Client)
+	filename := c.Args().Get(0)
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = client.AccountUpdate(context.Background(), &mastodon.Profile{
+		Header:     f,
+		HeaderName: filepath.Base(filename),
+	})
+	return err
+}
+
 func run() int {
 	app := makeApp()
 
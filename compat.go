@@ -0,0 +1,13 @@
+package mastodon
+
+import "strconv"
+
+// AccountIDString is a compatibility shim for callers migrating off the
+// old int64 account/relationship ID API: it converts a legacy numeric ID
+// into the string form every method in this package now expects.
+//
+// Deprecated: construct and store IDs as strings directly; this only
+// exists to ease the transition off int64 IDs.
+func AccountIDString(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
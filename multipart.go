@@ -0,0 +1,116 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path"
+	"strings"
+)
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// doAPIMultipart behaves like doAPI but sends a pre-built multipart/form-data
+// body instead of encoding url.Values, for endpoints that accept file
+// uploads alongside regular form fields.
+func (c *Client) doAPIMultipart(ctx context.Context, method string, uri string, body io.Reader, contentType string, res interface{}) error {
+	u, err := url.Parse(c.config.Server)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, uri)
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+	req = req.WithContext(ctx)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&e); err == nil && e.Error != "" {
+			return fmt.Errorf("mastodon: %s", e.Error)
+		}
+		return fmt.Errorf("mastodon: bad request: %d", resp.StatusCode)
+	}
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+// multipartBody builds a multipart/form-data body out of plain text fields
+// and named file readers, returning the encoded body and its content type.
+func multipartBody(fields url.Values, files map[string]namedReader) (io.Reader, string, error) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+
+	for key, values := range fields {
+		for _, v := range values {
+			if err := w.WriteField(key, v); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	for field, file := range files {
+		if err := writeFormFile(w, field, file); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf, w.FormDataContentType(), nil
+}
+
+// writeFormFile adds file as a multipart part, sniffing its content type
+// from the first bytes of its content instead of defaulting to
+// application/octet-stream the way multipart.Writer.CreateFormFile does.
+func writeFormFile(w *multipart.Writer, field string, file namedReader) error {
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file.Reader, sniff)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	sniff = sniff[:n]
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(field), quoteEscaper.Replace(file.Name)))
+	h.Set("Content-Type", http.DetectContentType(sniff))
+
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(sniff); err != nil {
+		return err
+	}
+	_, err = io.Copy(part, file.Reader)
+	return err
+}
+
+// namedReader pairs a file's content with the filename Mastodon should
+// record for it.
+type namedReader struct {
+	Name   string
+	Reader io.Reader
+}
@@ -0,0 +1,57 @@
+package mastodon
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestMultipartBodyDetectsContentType(t *testing.T) {
+	fields := url.Values{"display_name": {"Alice"}}
+	files := map[string]namedReader{
+		"avatar": {Name: "avatar.png", Reader: strings.NewReader("\x89PNG\r\n\x1a\n" + strings.Repeat("x", 32))},
+	}
+
+	body, contentType, err := multipartBody(fields, files)
+	if err != nil {
+		t.Fatalf("multipartBody: %v", err)
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("ParseMediaType: %v", err)
+	}
+
+	r := multipart.NewReader(body, params["boundary"])
+	var sawDisplayName, sawAvatar bool
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		switch part.FormName() {
+		case "display_name":
+			sawDisplayName = true
+		case "avatar":
+			sawAvatar = true
+			if got := part.Header.Get("Content-Type"); got != "image/png" {
+				t.Errorf("avatar Content-Type = %q, want image/png", got)
+			}
+			if part.FileName() != "avatar.png" {
+				t.Errorf("avatar FileName = %q, want avatar.png", part.FileName())
+			}
+		}
+	}
+	if !sawDisplayName {
+		t.Error("display_name field missing from body")
+	}
+	if !sawAvatar {
+		t.Error("avatar file part missing from body")
+	}
+}
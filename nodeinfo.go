@@ -0,0 +1,69 @@
+package mastodon
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// NodeInfo mirrors the subset of the NodeInfo 2.0 document
+// (http://nodeinfo.diaspora.software/ns/schema/2.0) this package cares
+// about: the server software name and version. It is used to detect
+// Pleroma/Akkoma/GoToSocial extensions.
+type NodeInfo struct {
+	Software struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"software"`
+}
+
+// nodeInfoCache caches the NodeInfo document per server, keyed by
+// c.config.Server rather than by *Client: keying by Client would keep
+// every Client that ever called getNodeInfo reachable from this
+// package-level map for the lifetime of the process. Servers are few
+// and stable, so this cache stays bounded without needing eviction.
+var (
+	nodeInfoCache   = map[string]*NodeInfo{}
+	nodeInfoCacheMu sync.Mutex
+)
+
+// getNodeInfo fetches the client's /nodeinfo/2.0 document, caching it so
+// repeated calls only hit the network once per server.
+func (c *Client) getNodeInfo(ctx context.Context) (*NodeInfo, error) {
+	nodeInfoCacheMu.Lock()
+	info, ok := nodeInfoCache[c.config.Server]
+	nodeInfoCacheMu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	info = &NodeInfo{}
+	if err := c.doAPI(ctx, http.MethodGet, "/nodeinfo/2.0", nil, info); err != nil {
+		return nil, err
+	}
+
+	nodeInfoCacheMu.Lock()
+	nodeInfoCache[c.config.Server] = info
+	nodeInfoCacheMu.Unlock()
+
+	return info, nil
+}
+
+// SoftwareName returns the server's NodeInfo software name, e.g.
+// "mastodon", "pleroma", "akkoma" or "gotosocial".
+func (c *Client) SoftwareName(ctx context.Context) (string, error) {
+	info, err := c.getNodeInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return info.Software.Name, nil
+}
+
+// SoftwareVersion returns the server's reported NodeInfo software version.
+func (c *Client) SoftwareVersion(ctx context.Context) (string, error) {
+	info, err := c.getNodeInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return info.Software.Version, nil
+}
@@ -0,0 +1,145 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Pagination specifies the range of items to fetch from a list endpoint,
+// and receives back the range of the next/previous pages so callers can
+// drive infinite scroll.
+type Pagination struct {
+	MaxID   string
+	SinceID string
+	MinID   string
+	Limit   int64
+}
+
+// setValues encodes the non-zero fields of p into params.
+func (p *Pagination) setValues(params url.Values) {
+	if p == nil {
+		return
+	}
+	if p.MaxID != "" {
+		params.Set("max_id", p.MaxID)
+	}
+	if p.SinceID != "" {
+		params.Set("since_id", p.SinceID)
+	}
+	if p.MinID != "" {
+		params.Set("min_id", p.MinID)
+	}
+	if p.Limit != 0 {
+		params.Set("limit", strconv.FormatInt(p.Limit, 10))
+	}
+}
+
+// readLink parses the rel="next"/rel="prev" entries of a response Link
+// header and writes their max_id/min_id query parameters back into p, so a
+// follow-up call can continue paging from where this one left off. A rel
+// that is absent from the header (e.g. rel="next" on the last page) clears
+// the corresponding field, so callers looping on "MaxID == \"\"" terminate
+// once there is nothing left to fetch.
+func (p *Pagination) readLink(h http.Header) {
+	if p == nil {
+		return
+	}
+	p.MaxID = ""
+	p.MinID = ""
+	for _, link := range parseLinkHeader(h.Get("Link")) {
+		switch link.rel {
+		case "next":
+			p.MaxID = link.query.Get("max_id")
+		case "prev":
+			p.MinID = link.query.Get("min_id")
+		}
+	}
+}
+
+// doAPIPaged behaves like doAPI for GET requests, except it also exposes
+// the response's Link header by reading it into pg. doAPI itself has no
+// way to return response headers to its caller, so list endpoints that
+// need pagination go through this instead. pg may be nil, in which case
+// this is equivalent to a GET through doAPI.
+func (c *Client) doAPIPaged(ctx context.Context, method string, uri string, params url.Values, res interface{}, pg *Pagination) error {
+	u, err := url.Parse(c.config.Server)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, uri)
+	if params != nil {
+		u.RawQuery = params.Encode()
+	}
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+	req = req.WithContext(ctx)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&e); err == nil && e.Error != "" {
+			return fmt.Errorf("mastodon: %s", e.Error)
+		}
+		return fmt.Errorf("mastodon: bad request: %d", resp.StatusCode)
+	}
+
+	pg.readLink(resp.Header)
+
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+type linkHeaderEntry struct {
+	rel   string
+	query url.Values
+}
+
+// parseLinkHeader parses an RFC 5988 Link header value, e.g.
+//
+//	<https://mastodon.example/api/v1/accounts/1/followers?max_id=7>; rel="next"
+func parseLinkHeader(v string) []linkHeaderEntry {
+	var entries []linkHeaderEntry
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		segs := strings.Split(part, ";")
+		uri := strings.Trim(strings.TrimSpace(segs[0]), "<>")
+		u, err := url.Parse(uri)
+		if err != nil {
+			continue
+		}
+		var rel string
+		for _, seg := range segs[1:] {
+			seg = strings.TrimSpace(seg)
+			if strings.HasPrefix(seg, "rel=") {
+				rel = strings.Trim(strings.TrimPrefix(seg, "rel="), `"`)
+			}
+		}
+		if rel == "" {
+			continue
+		}
+		entries = append(entries, linkHeaderEntry{rel: rel, query: u.Query()})
+	}
+	return entries
+}
@@ -0,0 +1,56 @@
+package mastodon
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPaginationReadLink(t *testing.T) {
+	tests := []struct {
+		name      string
+		link      string
+		wantMaxID string
+		wantMinID string
+	}{
+		{
+			name:      "next and prev",
+			link:      `<https://example.com/api/v1/accounts/1/followers?max_id=7>; rel="next", <https://example.com/api/v1/accounts/1/followers?min_id=9>; rel="prev"`,
+			wantMaxID: "7",
+			wantMinID: "9",
+		},
+		{
+			name:      "last page clears max_id",
+			link:      `<https://example.com/api/v1/accounts/1/followers?min_id=9>; rel="prev"`,
+			wantMaxID: "",
+			wantMinID: "9",
+		},
+		{
+			name:      "no link header clears both",
+			link:      "",
+			wantMaxID: "",
+			wantMinID: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pg := &Pagination{MaxID: "stale", MinID: "stale"}
+			h := http.Header{}
+			if tt.link != "" {
+				h.Set("Link", tt.link)
+			}
+			pg.readLink(h)
+			if pg.MaxID != tt.wantMaxID {
+				t.Errorf("MaxID = %q, want %q", pg.MaxID, tt.wantMaxID)
+			}
+			if pg.MinID != tt.wantMinID {
+				t.Errorf("MinID = %q, want %q", pg.MinID, tt.wantMinID)
+			}
+		})
+	}
+}
+
+func TestPaginationReadLinkNilReceiver(t *testing.T) {
+	var pg *Pagination
+	pg.readLink(http.Header{}) // must not panic
+}
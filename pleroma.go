@@ -0,0 +1,25 @@
+package mastodon
+
+// AccountPleroma holds the `pleroma` extension object that Pleroma/Akkoma
+// attach to Account payloads. Fields absent from stock Mastodon are decoded
+// into it instead of being dropped, so callers that care can branch on them.
+// Only Account carries this extension; the pleroma objects on Status and
+// Notification payloads are not decoded.
+type AccountPleroma struct {
+	Relationship        *Relationship `json:"relationship"`
+	IsAdmin             bool          `json:"is_admin"`
+	IsModerator         bool          `json:"is_moderator"`
+	IsConfirmed         bool          `json:"is_confirmed"`
+	AcceptsChatMessages bool          `json:"accepts_chat_messages"`
+	HideFavourites      *bool         `json:"hide_favorites"`
+}
+
+// pleromaRelationship returns the Relationship embedded in a's pleroma
+// extension object, for servers (older Pleroma/Akkoma) that surface it
+// there instead of via the top-level /api/v1/accounts/relationships array.
+func pleromaRelationship(a *Account) *Relationship {
+	if a == nil || a.Pleroma == nil {
+		return nil
+	}
+	return a.Pleroma.Relationship
+}